@@ -0,0 +1,256 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/tendolotan-alt/joevis-backend/internal/config"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type User struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	Email        string    `json:"email" gorm:"uniqueIndex"`
+	PasswordHash string    `json:"-"`
+	Role         string    `json:"role"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type RefreshToken struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    uint      `json:"user_id"`
+	TokenHash string    `json:"-" gorm:"uniqueIndex"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Claims is the JWT payload we issue for access tokens.
+type Claims struct {
+	Role string `json:"role"`
+	jwt.RegisteredClaims
+}
+
+func jwtSecret() []byte {
+	secret := config.Get().JWTSecret
+	if secret == "" {
+		// only reachable in dev mode; config.Load requires JWT_SECRET otherwise
+		secret = "dev-insecure-secret"
+	}
+	return []byte(secret)
+}
+
+func signAccessToken(u *User) (string, time.Time, error) {
+	exp := time.Now().Add(config.Get().JWTAccessTTL)
+	claims := Claims{
+		Role: u.Role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   strconvUint(u.ID),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(exp),
+		},
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	signed, err := token.SignedString(jwtSecret())
+	return signed, exp, err
+}
+
+// hashRefreshToken returns a deterministic lookup hash so a presented refresh
+// token can be matched against the stored row without keeping it in plaintext.
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func issueRefreshToken(u *User) (string, error) {
+	raw, err := randomToken(32)
+	if err != nil {
+		return "", err
+	}
+	rt := RefreshToken{
+		UserID:    u.ID,
+		TokenHash: hashRefreshToken(raw),
+		ExpiresAt: time.Now().Add(config.Get().JWTRefreshTTL),
+	}
+	if err := db.Create(&rt).Error; err != nil {
+		return "", err
+	}
+	return raw, nil
+}
+
+// jwtAuth verifies the Authorization bearer token and, if requiredRole is
+// non-empty, enforces that the token's role matches it. Parsed claims are
+// stored in the gin context under "claims" for downstream handlers.
+func jwtAuth(requiredRole string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, err := parseBearerClaims(c)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		if requiredRole != "" && claims.Role != requiredRole {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+			return
+		}
+		c.Set("claims", claims)
+		c.Next()
+	}
+}
+
+// optionalAuth parses a bearer token if one is present and stores the
+// claims in context under "claims", but never aborts the request — unlike
+// jwtAuth, a missing or invalid token just leaves "claims" unset. Routes
+// that are public but have an admin-only view (e.g. analytics) should sit
+// behind this and read the role back out of context.
+func optionalAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if claims, err := parseBearerClaims(c); err == nil {
+			c.Set("claims", claims)
+		}
+		c.Next()
+	}
+}
+
+func parseBearerClaims(c *gin.Context) (*Claims, error) {
+	header := c.GetHeader("Authorization")
+	if !strings.HasPrefix(header, "Bearer ") {
+		return nil, errors.New("missing bearer token")
+	}
+	raw := strings.TrimPrefix(header, "Bearer ")
+
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(raw, claims, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+		return jwtSecret(), nil
+	})
+	if err != nil || !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+	return claims, nil
+}
+
+type LoginPayload struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+}
+
+func login(c *gin.Context) {
+	var p LoginPayload
+	if err := c.ShouldBindJSON(&p); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var u User
+	if err := db.Where("email = ?", p.Email).First(&u).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid credentials"})
+		return
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(p.Password)); err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid credentials"})
+		return
+	}
+	access, exp, err := signAccessToken(&u)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "unable to sign token"})
+		return
+	}
+	refresh, err := issueRefreshToken(&u)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "unable to issue refresh token"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"access_token":  access,
+		"refresh_token": refresh,
+		"token_type":    "Bearer",
+		"expires_at":    exp,
+	})
+}
+
+type RefreshPayload struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+func refresh(c *gin.Context) {
+	var p RefreshPayload
+	if err := c.ShouldBindJSON(&p); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var rt RefreshToken
+	if err := db.Where("token_hash = ?", hashRefreshToken(p.RefreshToken)).First(&rt).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid refresh token"})
+		return
+	}
+	if time.Now().After(rt.ExpiresAt) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "refresh token expired"})
+		return
+	}
+	var u User
+	if err := db.First(&u, rt.UserID).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid refresh token"})
+		return
+	}
+	access, exp, err := signAccessToken(&u)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "unable to sign token"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"access_token": access,
+		"token_type":   "Bearer",
+		"expires_at":   exp,
+	})
+}
+
+type CreateUserPayload struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required,min=8"`
+	Role     string `json:"role" binding:"required"`
+}
+
+func createAdminUser(c *gin.Context) {
+	var p CreateUserPayload
+	if err := c.ShouldBindJSON(&p); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(p.Password), bcrypt.DefaultCost)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "unable to hash password"})
+		return
+	}
+	u := User{Email: p.Email, PasswordHash: string(hash), Role: p.Role}
+	if err := db.Create(&u).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "unable to create user"})
+		return
+	}
+	c.JSON(http.StatusCreated, u)
+}
+
+// seedAdminIfEmpty provisions the configured seed admin account on first run
+// so there's always a way to log in and create further users.
+func seedAdminIfEmpty(cfg *config.Config) {
+	var cnt int64
+	db.Model(&User{}).Count(&cnt)
+	if cnt != 0 || cfg.AdminSeedEmail == "" || cfg.AdminSeedPassword == "" {
+		return
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(cfg.AdminSeedPassword), bcrypt.DefaultCost)
+	if err != nil {
+		log.Println("seedAdminIfEmpty: unable to hash seed password:", err)
+		return
+	}
+	admin := User{Email: cfg.AdminSeedEmail, PasswordHash: string(hash), Role: "admin"}
+	if err := db.Create(&admin).Error; err != nil {
+		log.Println("seedAdminIfEmpty: unable to create seed admin:", err)
+	}
+}
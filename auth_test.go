@@ -0,0 +1,87 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func newTestContext(token string) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	req := httptest.NewRequest(http.MethodGet, "/admin/subscribers", nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	c.Request = req
+	return c, w
+}
+
+func signTestToken(t *testing.T, claims Claims, secret []byte) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	signed, err := token.SignedString(secret)
+	if err != nil {
+		t.Fatalf("failed to sign test token: %v", err)
+	}
+	return signed
+}
+
+func TestJwtAuthExpiredToken(t *testing.T) {
+	claims := Claims{
+		Role: "admin",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Hour)),
+			IssuedAt:  jwt.NewNumericDate(time.Now().Add(-2 * time.Hour)),
+		},
+	}
+	token := signTestToken(t, claims, jwtSecret())
+	c, w := newTestContext(token)
+
+	jwtAuth("admin")(c)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for expired token, got %d", w.Code)
+	}
+}
+
+func TestJwtAuthWrongSignature(t *testing.T) {
+	claims := Claims{
+		Role: "admin",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+	token := signTestToken(t, claims, []byte("not-the-real-secret"))
+	c, w := newTestContext(token)
+
+	jwtAuth("admin")(c)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for bad signature, got %d", w.Code)
+	}
+}
+
+func TestJwtAuthRoleMismatch(t *testing.T) {
+	claims := Claims{
+		Role: "user",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+	token := signTestToken(t, claims, jwtSecret())
+	c, w := newTestContext(token)
+
+	jwtAuth("admin")(c)
+
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for role mismatch, got %d", w.Code)
+	}
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Category struct {
+	ID       uint   `json:"id" gorm:"primaryKey"`
+	ParentID *uint  `json:"parent_id"`
+	Name     string `json:"name"`
+	Slug     string `json:"slug" gorm:"uniqueIndex"`
+	Sorter   int    `json:"sorter"`
+	Status   string `json:"status"` // active, hidden
+}
+
+// CategoryNested is a Category with its children attached, used to render
+// the tree returned by GET /categories.
+type CategoryNested struct {
+	Category
+	Children []CategoryNested `json:"children,omitempty"`
+}
+
+// categoryChildren walks rows (already loaded, ordered by sorter asc) and
+// builds the subtree rooted at parentID. Passing a nil parentID builds from
+// the top level.
+func categoryChildren(rows []Category, parentID *uint) []CategoryNested {
+	var out []CategoryNested
+	for _, r := range rows {
+		r := r
+		match := (r.ParentID == nil && parentID == nil) ||
+			(r.ParentID != nil && parentID != nil && *r.ParentID == *parentID)
+		if !match {
+			continue
+		}
+		out = append(out, CategoryNested{Category: r, Children: categoryChildren(rows, &r.ID)})
+	}
+	return out
+}
+
+// categoryDescendantIDs returns rootID plus every id reachable from it by
+// following ParentID links in rows.
+func categoryDescendantIDs(rows []Category, rootID uint) []uint {
+	ids := []uint{rootID}
+	var walk func(parentID uint)
+	walk = func(parentID uint) {
+		for _, r := range rows {
+			if r.ParentID != nil && *r.ParentID == parentID {
+				ids = append(ids, r.ID)
+				walk(r.ID)
+			}
+		}
+	}
+	walk(rootID)
+	return ids
+}
+
+// wouldCreateCycle reports whether re-parenting categoryID under newParentID
+// would make the tree cyclic, i.e. newParentID is categoryID itself or one
+// of its own descendants.
+func wouldCreateCycle(rows []Category, categoryID uint, newParentID *uint) bool {
+	if newParentID == nil {
+		return false
+	}
+	if *newParentID == categoryID {
+		return true
+	}
+	byID := make(map[uint]Category, len(rows))
+	for _, r := range rows {
+		byID[r.ID] = r
+	}
+	for cur := *newParentID; ; {
+		node, ok := byID[cur]
+		if !ok || node.ParentID == nil {
+			return false
+		}
+		if *node.ParentID == categoryID {
+			return true
+		}
+		cur = *node.ParentID
+	}
+}
+
+// ensureUncategorizedCategory returns the "Uncategorized" category,
+// creating it on first use. addMenu calls this to default new items that
+// don't specify a category_id.
+func ensureUncategorizedCategory() (Category, error) {
+	var uncategorized Category
+	if err := db.Where("slug = ?", "uncategorized").First(&uncategorized).Error; err != nil {
+		uncategorized = Category{Name: "Uncategorized", Slug: "uncategorized", Status: "active"}
+		if err := db.Create(&uncategorized).Error; err != nil {
+			return Category{}, err
+		}
+	}
+	return uncategorized, nil
+}
+
+// backfillUncategorized runs once at startup to fix up any MenuItem rows
+// left at the zero-value category_id by code predating the Category model.
+func backfillUncategorized() {
+	uncategorized, err := ensureUncategorizedCategory()
+	if err != nil {
+		return
+	}
+	db.Model(&MenuItem{}).Where("category_id = ?", 0).Update("category_id", uncategorized.ID)
+}
+
+// listCategories returns the nested tree under parent_id (top level if
+// omitted), optionally restricted to a single status.
+func listCategories(c *gin.Context) {
+	query := db.Order("sorter asc")
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+	var rows []Category
+	query.Find(&rows)
+
+	var parentID *uint
+	if raw := c.Query("parent_id"); raw != "" {
+		if v, err := strconv.ParseUint(raw, 10, 64); err == nil {
+			id := uint(v)
+			parentID = &id
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"items": categoryChildren(rows, parentID)})
+}
+
+type CategoryPayload struct {
+	ParentID *uint  `json:"parent_id"`
+	Name     string `json:"name" binding:"required"`
+	Slug     string `json:"slug" binding:"required"`
+	Sorter   int    `json:"sorter"`
+	Status   string `json:"status"`
+}
+
+func addCategory(c *gin.Context) {
+	var p CategoryPayload
+	if err := c.ShouldBindJSON(&p); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	cat := Category{ParentID: p.ParentID, Name: p.Name, Slug: p.Slug, Sorter: p.Sorter, Status: p.Status}
+	if err := db.Create(&cat).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "create failed"})
+		return
+	}
+	c.JSON(http.StatusCreated, cat)
+}
+
+// CategoryUpdatePayload uses pointer fields so editCategory can tell "the
+// client omitted this field" (leave it alone) apart from "the client sent
+// its zero value" (e.g. explicitly clearing status, or re-parenting to
+// root with parent_id: null).
+type CategoryUpdatePayload struct {
+	ParentID *uint   `json:"parent_id"`
+	Name     *string `json:"name"`
+	Slug     *string `json:"slug"`
+	Sorter   *int    `json:"sorter"`
+	Status   *string `json:"status"`
+}
+
+func editCategory(c *gin.Context) {
+	id := c.Param("id")
+	var cat Category
+	if err := db.First(&cat, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		return
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unable to read body"})
+		return
+	}
+	var p CategoryUpdatePayload
+	if err := json.Unmarshal(body, &p); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// parent_id is nullable, so p.ParentID alone can't distinguish "omitted"
+	// from "explicitly cleared to root" — check whether the key was sent.
+	var rawFields map[string]json.RawMessage
+	json.Unmarshal(body, &rawFields)
+	if _, provided := rawFields["parent_id"]; provided {
+		var rows []Category
+		db.Find(&rows)
+		if wouldCreateCycle(rows, cat.ID, p.ParentID) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "parent_id would create a cycle"})
+			return
+		}
+		cat.ParentID = p.ParentID
+	}
+	if p.Name != nil {
+		cat.Name = *p.Name
+	}
+	if p.Slug != nil {
+		cat.Slug = *p.Slug
+	}
+	if p.Sorter != nil {
+		cat.Sorter = *p.Sorter
+	}
+	if p.Status != nil {
+		cat.Status = *p.Status
+	}
+	db.Save(&cat)
+	c.JSON(http.StatusOK, cat)
+}
+
+func deleteCategory(c *gin.Context) {
+	id := c.Param("id")
+	var cat Category
+	if err := db.First(&cat, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+		return
+	}
+
+	var childCount int64
+	db.Model(&Category{}).Where("parent_id = ?", cat.ID).Count(&childCount)
+	if childCount > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "cannot delete a category with children; reparent or delete them first"})
+		return
+	}
+
+	if err := db.Delete(&Category{}, id).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "delete failed"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"ok": true})
+}
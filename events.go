@@ -0,0 +1,113 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tendolotan-alt/joevis-backend/internal/sse"
+)
+
+var hub = sse.NewHub()
+
+// adminOnlyTopics gates topics that carry data only admins should see.
+var adminOnlyTopics = map[string]bool{
+	"orders":      true,
+	"subscribers": true,
+}
+
+// Event type names published on the "menu" and "subscribers" topics. Typed
+// structs (below) keep every publish call site in sync with these shapes.
+const (
+	EventMenuCreated       = "MenuCreated"
+	EventMenuUpdated       = "MenuUpdated"
+	EventMenuDeleted       = "MenuDeleted"
+	EventSubscriberCreated = "SubscriberCreated"
+)
+
+type MenuEvent struct {
+	Type string   `json:"type"`
+	Item MenuItem `json:"item"`
+}
+
+type MenuDeletedEvent struct {
+	Type string `json:"type"`
+	ID   uint   `json:"id"`
+}
+
+type SubscriberEvent struct {
+	Type       string     `json:"type"`
+	Subscriber Subscriber `json:"subscriber"`
+}
+
+func publishMenuCreated(m MenuItem) {
+	hub.Publish("menu", MenuEvent{Type: EventMenuCreated, Item: m})
+}
+
+func publishMenuUpdated(m MenuItem) {
+	hub.Publish("menu", MenuEvent{Type: EventMenuUpdated, Item: m})
+}
+
+func publishMenuDeleted(id uint) {
+	hub.Publish("menu", MenuDeletedEvent{Type: EventMenuDeleted, ID: id})
+}
+
+func publishSubscriberCreated(s Subscriber) {
+	hub.Publish("subscribers", SubscriberEvent{Type: EventSubscriberCreated, Subscriber: s})
+}
+
+const sseHeartbeatInterval = 30 * time.Second
+
+// events upgrades the connection to text/event-stream and streams published
+// hub events for the requested topics until the client disconnects.
+func events(c *gin.Context) {
+	topics := strings.Split(c.Query("topics"), ",")
+	if len(topics) == 0 || topics[0] == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "topics query param is required"})
+		return
+	}
+	for _, t := range topics {
+		if !adminOnlyTopics[t] {
+			continue
+		}
+		claims, err := parseBearerClaims(c)
+		if err != nil || claims.Role != "admin" {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+			return
+		}
+		break
+	}
+
+	client := sse.NewClient(topics)
+	hub.Register(client)
+	defer hub.Unregister(client)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Writer.WriteHeader(http.StatusOK)
+	// flush an initial comment so proxies don't buffer the stream open
+	c.Writer.WriteString(":\n\n")
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	ctx := c.Request.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-heartbeat.C:
+			c.Writer.WriteString(":\n\n")
+			c.Writer.Flush()
+		case frame, ok := <-client.Send():
+			if !ok {
+				return
+			}
+			c.Writer.Write(frame)
+			c.Writer.Flush()
+		}
+	}
+}
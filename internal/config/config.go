@@ -0,0 +1,240 @@
+// Package config centralizes application configuration, replacing scattered
+// os.Getenv calls with a single Config struct loaded once at startup.
+//
+// Precedence, lowest to highest: built-in defaults, a config.yaml/config.toml
+// file (path from -config or CONFIG_PATH), then JOEVIS_* environment
+// variable overrides.
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds every tunable the app needs. Fields with a "-" yaml tag are
+// secrets and must never be exposed through the /admin/config endpoint.
+type Config struct {
+	Env        string `yaml:"env"`
+	ListenAddr string `yaml:"listen_addr"`
+	DBPath     string `yaml:"db_path"`
+	UploadsDir string `yaml:"uploads_dir"`
+
+	CORSAllowedOrigins []string `yaml:"cors_allowed_origins"`
+
+	JWTSecret     string        `yaml:"-"`
+	JWTAccessTTL  time.Duration `yaml:"jwt_access_ttl"`
+	JWTRefreshTTL time.Duration `yaml:"jwt_refresh_ttl"`
+
+	AdminSeedEmail    string `yaml:"admin_seed_email"`
+	AdminSeedPassword string `yaml:"-"`
+	SeedOnEmpty       bool   `yaml:"seed_on_empty"`
+
+	LogLevel string `yaml:"log_level"`
+
+	// StorageBackend selects the Uploader implementation: "local" or "s3".
+	StorageBackend string        `yaml:"storage_backend"`
+	MaxUploadBytes int64         `yaml:"max_upload_bytes"`
+	ThumbnailWidth int           `yaml:"thumbnail_width"`
+	S3Bucket       string        `yaml:"s3_bucket"`
+	S3Region       string        `yaml:"s3_region"`
+	S3Endpoint     string        `yaml:"s3_endpoint"`
+	S3PresignTTL   time.Duration `yaml:"s3_presign_ttl"`
+}
+
+func defaults() Config {
+	return Config{
+		Env:                "dev",
+		ListenAddr:         ":8080",
+		DBPath:             "joevis.db",
+		UploadsDir:         "uploads",
+		CORSAllowedOrigins: []string{"http://localhost:19006"},
+		JWTAccessTTL:       15 * time.Minute,
+		JWTRefreshTTL:      7 * 24 * time.Hour,
+		SeedOnEmpty:        true,
+		LogLevel:           "info",
+		StorageBackend:     "local",
+		MaxUploadBytes:     5 * 1024 * 1024,
+		ThumbnailWidth:     400,
+		S3PresignTTL:       15 * time.Minute,
+	}
+}
+
+var (
+	once    sync.Once
+	current *Config
+)
+
+// Get returns the process-wide Config, loading it on first call. It panics
+// if loading fails, since the app cannot run safely without valid config.
+func Get() *Config {
+	once.Do(func() {
+		cfg, err := Load()
+		if err != nil {
+			panic(err)
+		}
+		current = cfg
+	})
+	return current
+}
+
+// Load builds a Config from defaults, an optional config file, and
+// environment overrides. It is exported separately from Get so callers (and
+// tests) can load without mutating the package-wide singleton.
+func Load() (*Config, error) {
+	cfg := defaults()
+
+	if path := configFilePath(); path != "" {
+		if err := mergeFile(&cfg, path); err != nil {
+			return nil, fmt.Errorf("config: loading %s: %w", path, err)
+		}
+	}
+
+	mergeEnv(&cfg)
+
+	if cfg.Env != "dev" && cfg.JWTSecret == "" {
+		return nil, fmt.Errorf("config: JWT_SECRET is required outside dev mode")
+	}
+	return &cfg, nil
+}
+
+// configFilePath resolves the config file location from -config=<path> (or
+// -config <path>) on the command line, falling back to CONFIG_PATH.
+func configFilePath() string {
+	for i, arg := range os.Args[1:] {
+		if arg == "-config" || arg == "--config" {
+			if i+2 < len(os.Args) {
+				return os.Args[i+2]
+			}
+		}
+		if strings.HasPrefix(arg, "-config=") {
+			return strings.TrimPrefix(arg, "-config=")
+		}
+		if strings.HasPrefix(arg, "--config=") {
+			return strings.TrimPrefix(arg, "--config=")
+		}
+	}
+	return os.Getenv("CONFIG_PATH")
+}
+
+func mergeFile(cfg *Config, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml", ".toml":
+		// config.toml is accepted with YAML syntax rules too, since our
+		// config shape is flat key: value pairs valid in both formats.
+		return yaml.Unmarshal(data, cfg)
+	default:
+		return fmt.Errorf("unsupported config file extension: %s", path)
+	}
+}
+
+func mergeEnv(cfg *Config) {
+	if v := os.Getenv("JOEVIS_ENV"); v != "" {
+		cfg.Env = v
+	}
+	if v := os.Getenv("JOEVIS_LISTEN_ADDR"); v != "" {
+		cfg.ListenAddr = v
+	}
+	if v := os.Getenv("PORT"); v != "" {
+		cfg.ListenAddr = ":" + v
+	}
+	if v := os.Getenv("JOEVIS_DB_PATH"); v != "" {
+		cfg.DBPath = v
+	}
+	if v := os.Getenv("JOEVIS_UPLOADS_DIR"); v != "" {
+		cfg.UploadsDir = v
+	}
+	if v := os.Getenv("JOEVIS_CORS_ALLOWED_ORIGINS"); v != "" {
+		cfg.CORSAllowedOrigins = strings.Split(v, ",")
+	}
+	if v := os.Getenv("JWT_SECRET"); v != "" {
+		cfg.JWTSecret = v
+	}
+	if v := os.Getenv("JOEVIS_JWT_ACCESS_TTL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			cfg.JWTAccessTTL = d
+		}
+	}
+	if v := os.Getenv("JOEVIS_JWT_REFRESH_TTL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			cfg.JWTRefreshTTL = d
+		}
+	}
+	if v := os.Getenv("JOEVIS_ADMIN_SEED_EMAIL"); v != "" {
+		cfg.AdminSeedEmail = v
+	}
+	if v := os.Getenv("JOEVIS_ADMIN_SEED_PASSWORD"); v != "" {
+		cfg.AdminSeedPassword = v
+	}
+	if v := os.Getenv("JOEVIS_SEED_ON_EMPTY"); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			cfg.SeedOnEmpty = b
+		}
+	}
+	if v := os.Getenv("JOEVIS_LOG_LEVEL"); v != "" {
+		cfg.LogLevel = v
+	}
+	if v := os.Getenv("JOEVIS_STORAGE_BACKEND"); v != "" {
+		cfg.StorageBackend = v
+	}
+	if v := os.Getenv("JOEVIS_MAX_UPLOAD_BYTES"); v != "" {
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			cfg.MaxUploadBytes = n
+		}
+	}
+	if v := os.Getenv("JOEVIS_THUMBNAIL_WIDTH"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			cfg.ThumbnailWidth = n
+		}
+	}
+	if v := os.Getenv("JOEVIS_S3_BUCKET"); v != "" {
+		cfg.S3Bucket = v
+	}
+	if v := os.Getenv("JOEVIS_S3_REGION"); v != "" {
+		cfg.S3Region = v
+	}
+	if v := os.Getenv("JOEVIS_S3_ENDPOINT"); v != "" {
+		cfg.S3Endpoint = v
+	}
+	if v := os.Getenv("JOEVIS_S3_PRESIGN_TTL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			cfg.S3PresignTTL = d
+		}
+	}
+}
+
+// Public returns the subset of Config safe to expose over the API: secrets
+// (JWTSecret, AdminSeedPassword) are omitted.
+func (c Config) Public() map[string]interface{} {
+	return map[string]interface{}{
+		"env":                  c.Env,
+		"listen_addr":          c.ListenAddr,
+		"db_path":              c.DBPath,
+		"uploads_dir":          c.UploadsDir,
+		"cors_allowed_origins": c.CORSAllowedOrigins,
+		"jwt_access_ttl":       c.JWTAccessTTL.String(),
+		"jwt_refresh_ttl":      c.JWTRefreshTTL.String(),
+		"admin_seed_email":     c.AdminSeedEmail,
+		"seed_on_empty":        c.SeedOnEmpty,
+		"log_level":            c.LogLevel,
+		"storage_backend":      c.StorageBackend,
+		"max_upload_bytes":     c.MaxUploadBytes,
+		"thumbnail_width":      c.ThumbnailWidth,
+		"s3_bucket":            c.S3Bucket,
+		"s3_region":            c.S3Region,
+		"s3_presign_ttl":       c.S3PresignTTL.String(),
+	}
+}
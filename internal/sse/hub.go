@@ -0,0 +1,110 @@
+// Package sse implements a small pub/sub hub for broadcasting
+// server-sent-event frames to subscribed clients.
+package sse
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Event is a single message published to the hub, addressed to clients
+// subscribed to Topic.
+type Event struct {
+	Topic string
+	Data  interface{}
+}
+
+// Client is a single connected subscriber. send carries fully-formatted SSE
+// frames ready to be written to the response body.
+type Client struct {
+	send   chan []byte
+	topics map[string]bool
+}
+
+// NewClient returns a Client subscribed to the given topics, with a buffered
+// outbound channel so a slow writer doesn't block the hub.
+func NewClient(topics []string) *Client {
+	set := make(map[string]bool, len(topics))
+	for _, t := range topics {
+		set[t] = true
+	}
+	return &Client{send: make(chan []byte, 16), topics: set}
+}
+
+// Send returns the channel the client should read formatted frames from.
+// It is closed once the client is unregistered.
+func (c *Client) Send() <-chan []byte {
+	return c.send
+}
+
+func (c *Client) subscribed(topic string) bool {
+	return c.topics[topic]
+}
+
+// Hub fans out published events to every registered client subscribed to
+// the event's topic. All state is owned by the Run goroutine and mutated
+// only through the register/unregister/broadcast channels.
+type Hub struct {
+	clients    map[*Client]bool
+	register   chan *Client
+	unregister chan *Client
+	broadcast  chan Event
+}
+
+func NewHub() *Hub {
+	return &Hub{
+		clients:    make(map[*Client]bool),
+		register:   make(chan *Client),
+		unregister: make(chan *Client),
+		broadcast:  make(chan Event, 64),
+	}
+}
+
+// Run processes registrations and published events until the program exits.
+// Call it once, in its own goroutine.
+func (h *Hub) Run() {
+	for {
+		select {
+		case c := <-h.register:
+			h.clients[c] = true
+		case c := <-h.unregister:
+			if _, ok := h.clients[c]; ok {
+				delete(h.clients, c)
+				close(c.send)
+			}
+		case e := <-h.broadcast:
+			h.deliver(e)
+		}
+	}
+}
+
+func (h *Hub) deliver(e Event) {
+	payload, err := json.Marshal(e.Data)
+	if err != nil {
+		return
+	}
+	frame := []byte(fmt.Sprintf("event: %s\ndata: %s\n\n", e.Topic, payload))
+	for c := range h.clients {
+		if !c.subscribed(e.Topic) {
+			continue
+		}
+		select {
+		case c.send <- frame:
+		default:
+			// slow consumer; drop the frame rather than block the hub
+		}
+	}
+}
+
+func (h *Hub) Register(c *Client) {
+	h.register <- c
+}
+
+func (h *Hub) Unregister(c *Client) {
+	h.unregister <- c
+}
+
+// Publish broadcasts data to every client subscribed to topic.
+func (h *Hub) Publish(topic string, data interface{}) {
+	h.broadcast <- Event{Topic: topic, Data: data}
+}
@@ -0,0 +1,34 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// LocalUploader writes objects under Dir and serves them back from BaseURL
+// (expected to be mounted as a static route, e.g. r.Static(BaseURL, Dir)).
+type LocalUploader struct {
+	Dir     string
+	BaseURL string
+}
+
+func NewLocalUploader(dir, baseURL string) *LocalUploader {
+	return &LocalUploader{Dir: dir, BaseURL: baseURL}
+}
+
+func (l *LocalUploader) Put(_ context.Context, key string, r io.Reader, _ string) (string, error) {
+	if err := os.MkdirAll(l.Dir, 0755); err != nil {
+		return "", err
+	}
+	f, err := os.Create(filepath.Join(l.Dir, key))
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if _, err := io.Copy(f, r); err != nil {
+		return "", err
+	}
+	return l.BaseURL + "/" + key, nil
+}
@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// S3Config configures an S3Uploader. Endpoint is only set for S3-compatible
+// services (e.g. MinIO in local dev); leave it empty to use AWS.
+type S3Config struct {
+	Bucket     string
+	Region     string
+	Endpoint   string
+	PresignTTL time.Duration
+}
+
+// S3Uploader stores objects in a bucket and hands back presigned GET URLs,
+// since the bucket itself is expected to stay private.
+type S3Uploader struct {
+	client     *s3.Client
+	presign    *s3.PresignClient
+	bucket     string
+	presignTTL time.Duration
+}
+
+func NewS3Uploader(ctx context.Context, cfg S3Config) (*S3Uploader, error) {
+	optFns := []func(*awsconfig.LoadOptions) error{awsconfig.WithRegion(cfg.Region)}
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, optFns...)
+	if err != nil {
+		return nil, fmt.Errorf("storage: loading aws config: %w", err)
+	}
+
+	client := s3.NewFromConfig(awsCfg, func(o *s3.Options) {
+		if cfg.Endpoint != "" {
+			o.BaseEndpoint = aws.String(cfg.Endpoint)
+			o.UsePathStyle = true // required by MinIO and most S3-compatible endpoints
+		}
+	})
+
+	ttl := cfg.PresignTTL
+	if ttl <= 0 {
+		ttl = 15 * time.Minute
+	}
+
+	return &S3Uploader{
+		client:     client,
+		presign:    s3.NewPresignClient(client),
+		bucket:     cfg.Bucket,
+		presignTTL: ttl,
+	}, nil
+}
+
+func (u *S3Uploader) Put(ctx context.Context, key string, r io.Reader, contentType string) (string, error) {
+	_, err := u.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(u.bucket),
+		Key:         aws.String(key),
+		Body:        r,
+		ContentType: aws.String(contentType),
+	})
+	if err != nil {
+		return "", fmt.Errorf("storage: put object: %w", err)
+	}
+
+	req, err := u.presign.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(u.bucket),
+		Key:    aws.String(key),
+	}, s3.WithPresignExpires(u.presignTTL))
+	if err != nil {
+		return "", fmt.Errorf("storage: presign get object: %w", err)
+	}
+	return req.URL, nil
+}
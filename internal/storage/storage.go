@@ -0,0 +1,14 @@
+// Package storage abstracts where uploaded files end up, so the upload
+// handler doesn't need to know whether it's writing to local disk or S3.
+package storage
+
+import (
+	"context"
+	"io"
+)
+
+// Uploader persists a single object and returns a URL clients can fetch it
+// from (a static path for LocalUploader, a presigned GET URL for S3Uploader).
+type Uploader interface {
+	Put(ctx context.Context, key string, r io.Reader, contentType string) (url string, err error)
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// maxListLimit caps how many rows a single list request can return, regardless
+// of what the caller passes in limit/page_size.
+const maxListLimit = 200
+
+// ListOptions captures the pagination and sorting query params shared by all
+// list endpoints. Resource-specific filters are parsed separately by each
+// handler; only the sort column is checked against the allowlist passed in.
+type ListOptions struct {
+	Limit      int
+	Offset     int
+	Page       int
+	PageSize   int
+	SortColumn string
+	SortOrder  string
+	Query      string
+}
+
+// bindListOptions parses limit/offset (or page/page_size), sort_column,
+// sort_order and the free-text q param. sortColumn is validated against
+// allowedSort to avoid building an Order() clause from unsanitized input.
+func bindListOptions(c *gin.Context, allowedSort []string, defaultSort string) ListOptions {
+	pageSize := queryIntDefault(c, "page_size", 20)
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+	if pageSize > maxListLimit {
+		pageSize = maxListLimit
+	}
+	page := queryIntDefault(c, "page", 1)
+	if page <= 0 {
+		page = 1
+	}
+
+	limit := pageSize
+	offset := (page - 1) * pageSize
+	if l := queryIntDefault(c, "limit", 0); l > 0 {
+		limit = l
+		if limit > maxListLimit {
+			limit = maxListLimit
+		}
+	}
+	if o := queryIntDefault(c, "offset", -1); o >= 0 {
+		offset = o
+	}
+
+	sortColumn := c.DefaultQuery("sort_column", defaultSort)
+	if !stringInSlice(allowedSort, sortColumn) {
+		sortColumn = defaultSort
+	}
+	sortOrder := strings.ToLower(c.DefaultQuery("sort_order", "desc"))
+	if sortOrder != "asc" && sortOrder != "desc" {
+		sortOrder = "desc"
+	}
+
+	return ListOptions{
+		Limit:      limit,
+		Offset:     offset,
+		Page:       page,
+		PageSize:   pageSize,
+		SortColumn: sortColumn,
+		SortOrder:  sortOrder,
+		Query:      c.Query("q"),
+	}
+}
+
+func (o ListOptions) apply(query *gorm.DB) *gorm.DB {
+	return query.Order(o.SortColumn + " " + o.SortOrder).Limit(o.Limit).Offset(o.Offset)
+}
+
+// listEnvelope wraps query results in the {items, total, page, page_size}
+// shape shared by every list endpoint.
+func listEnvelope(items interface{}, total int64, o ListOptions) gin.H {
+	return gin.H{
+		"items":     items,
+		"total":     total,
+		"page":      o.Page,
+		"page_size": o.PageSize,
+	}
+}
+
+func queryIntDefault(c *gin.Context, key string, def int) int {
+	raw := c.Query(key)
+	if raw == "" {
+		return def
+	}
+	v, err := strconv.Atoi(raw)
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+func stringInSlice(haystack []string, needle string) bool {
+	for _, s := range haystack {
+		if s == needle {
+			return true
+		}
+	}
+	return false
+}
@@ -1,16 +1,15 @@
 package main
 
 import (
-	"fmt"
 	"log"
 	"net/http"
 	"os"
-	"path/filepath"
 	"strconv"
 	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/tendolotan-alt/joevis-backend/internal/config"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
@@ -22,6 +21,7 @@ type MenuItem struct {
 	Price       float64   `json:"price"`
 	MealType    string    `json:"meal_type"` // weekday-breakfast, weekday-lunch, weekend-breakfast, weekend-lunch
 	ImageURL    string    `json:"image_url"`
+	CategoryID  uint      `json:"category_id" gorm:"index"`
 	CreatedAt   time.Time `json:"created_at"`
 }
 
@@ -46,25 +46,36 @@ type Order struct {
 var db *gorm.DB
 
 func main() {
+	cfg := config.Get()
+
 	var err error
-	db, err = gorm.Open(sqlite.Open("joevis.db"), &gorm.Config{})
+	db, err = gorm.Open(sqlite.Open(cfg.DBPath), &gorm.Config{})
 	if err != nil {
 		log.Fatal("failed to connect db:", err)
 	}
 
 	// migrations
-	if err := db.AutoMigrate(&MenuItem{}, &Subscriber{}, &Order{}); err != nil {
+	if err := db.AutoMigrate(&MenuItem{}, &Subscriber{}, &Order{}, &User{}, &RefreshToken{}, &Category{}, &Asset{}); err != nil {
 		log.Fatal(err)
 	}
+	backfillUncategorized()
+	seedAdminIfEmpty(cfg)
+
+	if err := initUploader(cfg); err != nil {
+		log.Fatal("failed to init uploader:", err)
+	}
 
 	// create uploads dir
-	os.MkdirAll("uploads", 0755)
+	os.MkdirAll(cfg.UploadsDir, 0755)
+
+	go hub.Run()
 
 	r := gin.Default()
 
-	// allow requests from Expo / phone
+	// allow requests from Expo / phone; origins come from config, not "*",
+	// since browsers reject a wildcard origin on credentialed requests
 	r.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{"*"},
+		AllowOrigins:     cfg.CORSAllowedOrigins,
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
 		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
 		AllowCredentials: true,
@@ -76,45 +87,91 @@ func main() {
 	r.GET("/menus", getMenus)
 	r.GET("/menus/:id", getMenu)
 	r.POST("/subscribe", subscribe)
-	r.GET("/analytics", analytics)           // aggregated but safe for admin; protected by password in query for simplicity
+	r.GET("/analytics", optionalAuth(), analytics) // aggregated; includes admin-only fields when a valid admin token is presented
 	r.GET("/recommendations", recommendations)
-	r.Static("/uploads", "./uploads")
+	r.GET("/categories", listCategories)
+	r.GET("/events", events)
+	r.Static("/uploads", cfg.UploadsDir)
 
-	// admin endpoints (simple password check via header x-admin-pw)
-	admin := r.Group("/admin", adminAuth())
+	r.POST("/auth/login", login)
+	r.POST("/auth/refresh", refresh)
+
+	// admin endpoints, gated by a JWT with role "admin"
+	admin := r.Group("/admin", jwtAuth("admin"))
 	{
 		admin.POST("/menu", addMenu)
 		admin.PUT("/menu/:id", editMenu)
 		admin.DELETE("/menu/:id", deleteMenu)
 		admin.POST("/upload", uploadImage)
 		admin.GET("/subscribers", listSubscribers)
+		admin.GET("/orders", listOrders)
+		admin.POST("/users", createAdminUser)
+		admin.POST("/categories", addCategory)
+		admin.PUT("/categories/:id", editCategory)
+		admin.DELETE("/categories/:id", deleteCategory)
+		admin.GET("/config", adminGetConfig)
 	}
 
 	// optionally seed if empty
-	seedIfEmpty()
-
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	if cfg.SeedOnEmpty {
+		seedIfEmpty()
 	}
-	log.Println("starting backend on port", port)
-	r.Run(":" + port)
+
+	log.Println("starting backend on", cfg.ListenAddr)
+	r.Run(cfg.ListenAddr)
+}
+
+func adminGetConfig(c *gin.Context) {
+	c.JSON(http.StatusOK, config.Get().Public())
 }
 
 func health(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"status": "ok"})
 }
 
+var menuSortableColumns = []string{"id", "name", "price", "meal_type", "created_at"}
+
 func getMenus(c *gin.Context) {
-	var items []MenuItem
-	// optional meal filter
-	mealType := c.Query("meal_type")
-	if mealType != "" {
-		db.Where("meal_type = ?", mealType).Order("created_at desc").Find(&items)
-	} else {
-		db.Order("created_at desc").Find(&items)
+	opts := bindListOptions(c, menuSortableColumns, "created_at")
+
+	query := db.Model(&MenuItem{})
+	if mealType := c.Query("filter_meal_type"); mealType != "" {
+		query = query.Where("meal_type = ?", mealType)
 	}
-	c.JSON(http.StatusOK, gin.H{"items": items})
+	if min := c.Query("filter_price_min"); min != "" {
+		if v, err := strconv.ParseFloat(min, 64); err == nil {
+			query = query.Where("price >= ?", v)
+		}
+	}
+	if max := c.Query("filter_price_max"); max != "" {
+		if v, err := strconv.ParseFloat(max, 64); err == nil {
+			query = query.Where("price <= ?", v)
+		}
+	}
+	if opts.Query != "" {
+		like := "%" + opts.Query + "%"
+		query = query.Where("name LIKE ? OR description LIKE ?", like, like)
+	}
+	if categoryIDRaw := c.Query("category_id"); categoryIDRaw != "" {
+		if v, err := strconv.ParseUint(categoryIDRaw, 10, 64); err == nil {
+			categoryID := uint(v)
+			if c.Query("include_descendants") == "true" {
+				var rows []Category
+				db.Order("sorter asc").Find(&rows)
+				query = query.Where("category_id IN ?", categoryDescendantIDs(rows, categoryID))
+			} else {
+				query = query.Where("category_id = ?", categoryID)
+			}
+		}
+	}
+
+	var total int64
+	query.Count(&total)
+
+	var items []MenuItem
+	opts.apply(query).Find(&items)
+
+	c.JSON(http.StatusOK, listEnvelope(items, total, opts))
 }
 
 func getMenu(c *gin.Context) {
@@ -152,6 +209,7 @@ func subscribe(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "unable to save subscriber"})
 		return
 	}
+	publishSubscriberCreated(sub)
 	c.JSON(http.StatusCreated, gin.H{"ok": true, "subscriber": sub})
 }
 
@@ -177,8 +235,13 @@ func recommendations(c *gin.Context) {
 }
 
 func analytics(c *gin.Context) {
-	// basic analytics; require admin pw for full data
-	adminPW := c.GetHeader("x-admin-pw")
+	// basic analytics; recent_subscribers is only included for admin tokens.
+	// optionalAuth (ahead of this route) populates "claims" when a valid
+	// token was presented, so we just read it back from context.
+	var claims *Claims
+	if v, ok := c.Get("claims"); ok {
+		claims, _ = v.(*Claims)
+	}
 	total := int64(0)
 	active := int64(0)
 	db.Model(&Subscriber{}).Count(&total)
@@ -209,7 +272,7 @@ func analytics(c *gin.Context) {
 		"top_items":         topItems,
 	}
 	// include list of recent subscribers only for admin
-	if adminPW != "" && adminPW == os.Getenv("ADMIN_PASSWORD") {
+	if claims != nil && claims.Role == "admin" {
 		var subs []Subscriber
 		db.Order("created_at desc").Limit(10).Find(&subs)
 		result["recent_subscribers"] = subs
@@ -234,33 +297,28 @@ func seedIfEmpty() {
 	}
 }
 
-func uploadImage(c *gin.Context) {
-	file, err := c.FormFile("file")
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "no file"})
-		return
-	}
-	ext := filepath.Ext(file.Filename)
-	name := fmt.Sprintf("%d%s", time.Now().UnixNano(), ext)
-	path := filepath.Join("uploads", name)
-	if err := c.SaveUploadedFile(file, path); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "save failed"})
-		return
-	}
-	url := "/uploads/" + name
-	c.JSON(http.StatusOK, gin.H{"url": url})
-}
-
 func addMenu(c *gin.Context) {
 	var m MenuItem
 	if err := c.ShouldBindJSON(&m); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if m.CategoryID == 0 {
+		uncategorized, err := ensureUncategorizedCategory()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "unable to default category"})
+			return
+		}
+		m.CategoryID = uncategorized.ID
+	} else if err := db.First(&Category{}, m.CategoryID).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category_id"})
+		return
+	}
 	if err := db.Create(&m).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "create failed"})
 		return
 	}
+	publishMenuCreated(m)
 	c.JSON(http.StatusCreated, m)
 }
 
@@ -283,7 +341,15 @@ func editMenu(c *gin.Context) {
 	if payload.ImageURL != "" {
 		m.ImageURL = payload.ImageURL
 	}
+	if payload.CategoryID != 0 {
+		if err := db.First(&Category{}, payload.CategoryID).Error; err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category_id"})
+			return
+		}
+		m.CategoryID = payload.CategoryID
+	}
 	db.Save(&m)
+	publishMenuUpdated(m)
 	c.JSON(http.StatusOK, m)
 }
 
@@ -293,23 +359,55 @@ func deleteMenu(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "delete failed"})
 		return
 	}
+	if v, err := strconv.ParseUint(id, 10, 64); err == nil {
+		publishMenuDeleted(uint(v))
+	}
 	c.JSON(http.StatusOK, gin.H{"ok": true})
 }
 
+var subscriberSortableColumns = []string{"id", "name", "email", "start_date", "end_date", "created_at"}
+
 func listSubscribers(c *gin.Context) {
+	opts := bindListOptions(c, subscriberSortableColumns, "created_at")
+
+	query := db.Model(&Subscriber{})
+	if active := c.Query("filter_active"); active != "" {
+		if v, err := strconv.ParseBool(active); err == nil {
+			query = query.Where("active = ?", v)
+		}
+	}
+	if opts.Query != "" {
+		like := "%" + opts.Query + "%"
+		query = query.Where("name LIKE ? OR email LIKE ?", like, like)
+	}
+
+	var total int64
+	query.Count(&total)
+
 	var subs []Subscriber
-	db.Order("created_at desc").Find(&subs)
-	c.JSON(http.StatusOK, subs)
+	opts.apply(query).Find(&subs)
+
+	c.JSON(http.StatusOK, listEnvelope(subs, total, opts))
 }
 
-// adminAuth middleware checks x-admin-pw header against ADMIN_PASSWORD env var
-func adminAuth() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		pw := c.GetHeader("x-admin-pw")
-		if pw == "" || pw != os.Getenv("ADMIN_PASSWORD") {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
-			return
-		}
-		c.Next()
+var orderSortableColumns = []string{"id", "date", "subscriber_id", "menu_item_id"}
+
+func listOrders(c *gin.Context) {
+	opts := bindListOptions(c, orderSortableColumns, "date")
+
+	query := db.Model(&Order{})
+	if subscriberID := c.Query("filter_subscriber_id"); subscriberID != "" {
+		query = query.Where("subscriber_id = ?", subscriberID)
 	}
-}
\ No newline at end of file
+	if menuItemID := c.Query("filter_menu_item_id"); menuItemID != "" {
+		query = query.Where("menu_item_id = ?", menuItemID)
+	}
+
+	var total int64
+	query.Count(&total)
+
+	var orders []Order
+	opts.apply(query).Find(&orders)
+
+	c.JSON(http.StatusOK, listEnvelope(orders, total, opts))
+}
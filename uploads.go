@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/tendolotan-alt/joevis-backend/internal/config"
+	"github.com/tendolotan-alt/joevis-backend/internal/storage"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// Asset records a processed upload: the stored original plus its thumbnail.
+type Asset struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	Key          string    `json:"key"`
+	MIME         string    `json:"mime"`
+	Width        int       `json:"width"`
+	Height       int       `json:"height"`
+	Size         int64     `json:"size"`
+	ThumbnailKey string    `json:"thumbnail_key"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+var allowedUploadMIMEs = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/webp": true,
+}
+
+// uploader is the process-wide Uploader, built once at startup by
+// initUploader. Building an S3Uploader resolves AWS credentials (file/IMDS
+// I/O), so uploadImage must not reconstruct it per request.
+var uploader storage.Uploader
+
+// initUploader builds the configured Uploader and stores it for uploadImage
+// to use. Call once during startup, after config.Get() is available.
+func initUploader(cfg *config.Config) error {
+	u, err := newUploader(cfg)
+	if err != nil {
+		return err
+	}
+	uploader = u
+	return nil
+}
+
+func newUploader(cfg *config.Config) (storage.Uploader, error) {
+	switch cfg.StorageBackend {
+	case "s3":
+		return storage.NewS3Uploader(context.Background(), storage.S3Config{
+			Bucket:     cfg.S3Bucket,
+			Region:     cfg.S3Region,
+			Endpoint:   cfg.S3Endpoint,
+			PresignTTL: cfg.S3PresignTTL,
+		})
+	default:
+		return storage.NewLocalUploader(cfg.UploadsDir, "/uploads"), nil
+	}
+}
+
+// uploadImage validates and sanitizes an uploaded image: it sniffs the real
+// content type (ignoring the client-supplied filename/extension), caps the
+// size, re-encodes as JPEG to strip EXIF, and stores both the original and a
+// thumbnail through the configured Uploader.
+func uploadImage(c *gin.Context) {
+	cfg := config.Get()
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, cfg.MaxUploadBytes)
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "no file, or file exceeds the size limit"})
+		return
+	}
+	src, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unable to read file"})
+		return
+	}
+	defer src.Close()
+
+	sniff := make([]byte, 512)
+	n, _ := io.ReadFull(src, sniff)
+	contentType := http.DetectContentType(sniff[:n])
+	if !allowedUploadMIMEs[contentType] {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unsupported file type: " + contentType})
+		return
+	}
+
+	img, _, err := image.Decode(io.MultiReader(bytes.NewReader(sniff[:n]), src))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "unable to decode image"})
+		return
+	}
+
+	original, err := encodeJPEG(img, 90)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "unable to encode image"})
+		return
+	}
+	thumb, err := encodeJPEG(scaleImage(img, cfg.ThumbnailWidth), 85)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "unable to encode thumbnail"})
+		return
+	}
+
+	if uploader == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "storage backend unavailable"})
+		return
+	}
+
+	key := uuid.NewString() + ".jpg"
+	thumbKey := uuid.NewString() + "_thumb.jpg"
+
+	ctx := c.Request.Context()
+	url, err := uploader.Put(ctx, key, bytes.NewReader(original), "image/jpeg")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "save failed"})
+		return
+	}
+	thumbURL, err := uploader.Put(ctx, thumbKey, bytes.NewReader(thumb), "image/jpeg")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "save failed"})
+		return
+	}
+
+	bounds := img.Bounds()
+	asset := Asset{
+		Key:          key,
+		MIME:         "image/jpeg",
+		Width:        bounds.Dx(),
+		Height:       bounds.Dy(),
+		Size:         int64(len(original)),
+		ThumbnailKey: thumbKey,
+	}
+	if err := db.Create(&asset).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "unable to save asset"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"url": url, "thumbnail_url": thumbURL, "asset": asset})
+}
+
+func encodeJPEG(img image.Image, quality int) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// scaleImage resizes img to maxWidth, preserving aspect ratio. It is a no-op
+// (returns img unchanged) if img is already narrower than maxWidth.
+func scaleImage(img image.Image, maxWidth int) image.Image {
+	bounds := img.Bounds()
+	if bounds.Dx() <= maxWidth {
+		return img
+	}
+	height := int(float64(bounds.Dy()) * float64(maxWidth) / float64(bounds.Dx()))
+	dst := image.NewRGBA(image.Rect(0, 0, maxWidth, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Over, nil)
+	return dst
+}